@@ -0,0 +1,48 @@
+package ethereum
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AddrLocker hands out one mutex per address, so unrelated accounts never
+// block each other while still serializing the "get nonce -> sign -> send ->
+// increment" critical section for any single account.
+type AddrLocker struct {
+	mu    sync.RWMutex
+	locks map[common.Address]*sync.Mutex
+}
+
+// NewAddrLocker creates an empty AddrLocker.
+func NewAddrLocker() *AddrLocker {
+	return &AddrLocker{locks: make(map[common.Address]*sync.Mutex)}
+}
+
+func (l *AddrLocker) lockFor(addr common.Address) *sync.Mutex {
+	l.mu.RLock()
+	mu, ok := l.locks[addr]
+	l.mu.RUnlock()
+	if ok {
+		return mu
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if mu, ok := l.locks[addr]; ok {
+		return mu
+	}
+	mu = new(sync.Mutex)
+	l.locks[addr] = mu
+	return mu
+}
+
+// Lock acquires addr's mutex, blocking until it's available.
+func (l *AddrLocker) Lock(addr common.Address) {
+	l.lockFor(addr).Lock()
+}
+
+// Unlock releases addr's mutex.
+func (l *AddrLocker) Unlock(addr common.Address) {
+	l.lockFor(addr).Unlock()
+}
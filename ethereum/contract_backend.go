@@ -2,13 +2,19 @@ package ethereum
 
 import (
 	"context"
+	"errors"
 	"math/big"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/forta-protocol/forta-node/utils"
 
+	geth "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -19,133 +25,585 @@ const (
 	maxNonceDrift = 50
 )
 
-// ContractBackend is the same interface.
+// TxMode selects the transaction pricing strategy used by contractBackend.
+type TxMode int
+
+const (
+	// TxModeLegacy prices and sends legacy (type-0) transactions via SuggestGasPrice.
+	TxModeLegacy TxMode = iota
+	// TxModeDynamicFee prices and sends EIP-1559 (type-2) transactions via SuggestGasTipCap.
+	TxModeDynamicFee
+)
+
+// ContractBackend is the same interface, plus EIP-1559 fee suggestion and
+// the Transact method needed to transact safely from multiple goroutines
+// and accounts. *contractBackend satisfies it, combining rawBackend
+// (delegated straight to the underlying client) with its own nonce-aware
+// overrides. It's safe to hand straight to go-ethereum's generated contract
+// bindings (bind.BoundContract): PendingNonceAt and SendTransaction each
+// lock their own account for the whole call, so bindings that call them
+// separately, with no knowledge of this module, never corrupt the nonce
+// state. Two such callers can still be handed the same nonce between the
+// two calls; Transact is the way to avoid that.
 type ContractBackend interface {
 	bind.ContractBackend
+	geth.GasPricer1559
+	Transactor
+
+	// BlockNumber and TransactionReceipt are what Sender polls to detect
+	// confirmations and decide when to resend a stuck transaction.
+	BlockNumber(ctx context.Context) (uint64, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
 }
 
-// contractBackend is a wrapper of go-ethereum client. This is useful for implementing
-// extra features. It's not thread-safe.
-type contractBackend struct {
+// rawBackend is the part of ContractBackend that ethclient.Client already
+// implements and that contractBackend can delegate to unchanged, plus
+// ChainID, which txSigner needs.
+type rawBackend interface {
+	bind.ContractBackend
+	geth.GasPricer1559
+
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Transactor is for callers that sign transactions outside of
+// SendTransaction (e.g. Sender resending a stuck tx at a bumped price) and
+// need the nonce fetch, external signing step, and send to all happen under
+// one held lock. PendingNonceAt and SendTransaction can't be composed into
+// that on their own: they each take and release the account's lock for just
+// their own call, so calling them back to back still leaves a window for
+// another goroutine to get the same nonce in between.
+type Transactor interface {
+	// Transact fetches addr's next nonce, builds a transaction from it via
+	// build, signs the result with sign, and sends it, holding addr's nonce
+	// lock for the whole sequence.
+	Transact(ctx context.Context, addr common.Address, build func(nonce uint64) (*types.Transaction, error), sign bind.SignerFn) (*types.Transaction, error)
+}
+
+// sentTxInfo lets the reorg watcher notice when a tx this backend sent and
+// saw mined later disappears from the canonical chain.
+type sentTxInfo struct {
+	nonce      uint64
+	mined      bool
+	minedBlock uint64
+}
+
+// nonceState is the locally tracked nonce bookkeeping for one account. It's
+// only ever read or written while that account's AddrLocker mutex is held.
+type nonceState struct {
 	localNonce      uint64
 	lastServerNonce uint64
+	sent            map[common.Hash]*sentTxInfo
+}
+
+func (st *nonceState) increment(tx *types.Transaction) {
+	if newNonce := tx.Nonce() + 1; newNonce > st.localNonce {
+		st.localNonce = newNonce
+	}
+}
 
-	gasPrice        *big.Int
-	gasPriceUpdated time.Time
-	maxPrice        *big.Int
+func (st *nonceState) reset() {
+	if st.lastServerNonce < st.localNonce {
+		st.localNonce = st.lastServerNonce
+	}
+}
+
+// contractBackend is a wrapper of go-ethereum client. This is useful for
+// implementing extra features. Per-account state is guarded by locker, so
+// multiple goroutines and multiple sender accounts can share one backend.
+type contractBackend struct {
+	locker   *AddrLocker
+	statesMu sync.Mutex
+	nonces   map[common.Address]*nonceState
+
+	signerMu sync.Mutex
+	signer   types.Signer
+
+	gasMonitor *GasPriceMonitor
+
+	gasTipCapMu      sync.Mutex
+	gasTipCap        *big.Int
+	gasTipCapUpdated time.Time
+
+	maxPrice *big.Int
+
+	txMode TxMode
 
-	ContractBackend
+	// reorgWatchActive is set once WatchReorgs starts, so SendTransaction
+	// only pays to track sent txs in st.sent when something will actually
+	// drain that map; otherwise it's an unbounded leak for the life of the
+	// process.
+	reorgWatchActive atomic.Bool
+
+	rawBackend
+}
+
+// NewContractBackend creates a new contract backend by wrapping
+// `ethclient.Client`. It starts a GasPriceMonitor against the raw client,
+// configured with maxPrice and gasPriceMode; SuggestGasPrice delegates to it.
+func NewContractBackend(client *rpc.Client, maxPrice *big.Int, txMode TxMode, gasPriceMode GasPriceMode) ContractBackend {
+	return NewContractBackendWithClient(ethclient.NewClient(client), maxPrice, txMode, gasPriceMode)
+}
+
+// NewContractBackendWithClient is like NewContractBackend but takes an
+// already constructed ethclient.Client, e.g. one handed out by a simulated
+// backend in tests.
+func NewContractBackendWithClient(ec *ethclient.Client, maxPrice *big.Int, txMode TxMode, gasPriceMode GasPriceMode) ContractBackend {
+	return newContractBackend(ec, maxPrice, txMode, gasPriceMode)
 }
 
-// NewContractBackend creates a new contract backend by wrapping `ethclient.Client`.
-func NewContractBackend(client *rpc.Client, maxPrice *big.Int) bind.ContractBackend {
+// NewContractBackendWithRawBackend is like NewContractBackendWithClient, but
+// accepts any client with the same read/write/pricing surface instead of
+// requiring the concrete *ethclient.Client, e.g.
+// ethclient/simulated.Backend.Client(), whose interface return type
+// deliberately hides the concrete *ethclient.Client it wraps.
+func NewContractBackendWithRawBackend(rb rawBackend, maxPrice *big.Int, txMode TxMode, gasPriceMode GasPriceMode) ContractBackend {
+	return newContractBackend(rb, maxPrice, txMode, gasPriceMode)
+}
+
+// newContractBackend is the shared body of NewContractBackendWithClient and
+// NewContractBackendWithRawBackend (which the simulated package's
+// constructor calls). It takes rawBackend rather than the
+// concrete *ethclient.Client so callers that only have an interface-shaped
+// client (e.g. ethclient/simulated.Backend.Client(), which deliberately
+// hides its concrete *ethclient.Client to prevent extraction) can still
+// build a ContractBackend around it.
+func newContractBackend(rb rawBackend, maxPrice *big.Int, txMode TxMode, gasPriceMode GasPriceMode) ContractBackend {
+	monitor := NewGasPriceMonitor(rb, GasPriceMonitorConfig{
+		MaxPrice: maxPrice,
+		Mode:     gasPriceMode,
+	})
+	monitor.Start(context.Background())
 	return &contractBackend{
-		ContractBackend: ethclient.NewClient(client),
-		maxPrice:        maxPrice,
+		rawBackend: rb,
+		locker:     NewAddrLocker(),
+		nonces:     make(map[common.Address]*nonceState),
+		gasMonitor: monitor,
+		maxPrice:   maxPrice,
+		txMode:     txMode,
 	}
 }
 
-// SuggestGasPrice retrieves the currently suggested gas price and adds 10%
-func (cb *contractBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
-	if cb.gasPrice != nil && time.Since(cb.gasPriceUpdated) < 1*time.Minute {
-		return cb.gasPrice, nil
+// Transact fetches addr's next nonce, builds and signs a transaction with
+// it, and sends it, holding addr's nonce lock for the whole sequence. See
+// Transactor.
+func (cb *contractBackend) Transact(ctx context.Context, addr common.Address, build func(nonce uint64) (*types.Transaction, error), sign bind.SignerFn) (*types.Transaction, error) {
+	cb.locker.Lock(addr)
+	defer cb.locker.Unlock(addr)
+
+	nonce, err := cb.pendingNonceAtLocked(ctx, addr)
+	if err != nil {
+		return nil, err
 	}
-	gp, err := cb.ContractBackend.SuggestGasPrice(ctx)
+	tx, err := build(nonce)
 	if err != nil {
 		return nil, err
 	}
-	utils.AddPercentage(gp, 10)
-	if cb.maxPrice != nil {
-		if gp.Cmp(cb.maxPrice) == 1 {
-			log.WithFields(log.Fields{
-				"suggested": gp.Int64(),
-				"maximum":   cb.maxPrice.Int64(),
-			}).Warn("returning maximum price")
-			return cb.maxPrice, nil
-		}
+	signedTx, err := sign(addr, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := cb.sendTransactionLocked(ctx, signedTx, addr); err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
+
+func (cb *contractBackend) stateFor(addr common.Address) *nonceState {
+	cb.statesMu.Lock()
+	defer cb.statesMu.Unlock()
+	st, ok := cb.nonces[addr]
+	if !ok {
+		st = &nonceState{sent: make(map[common.Hash]*sentTxInfo)}
+		cb.nonces[addr] = st
+	}
+	return st
+}
+
+// txSigner returns the signer used to recover the sender of an already
+// signed transaction, so SendTransaction knows which account's nonce state
+// to update.
+func (cb *contractBackend) txSigner(ctx context.Context) (types.Signer, error) {
+	cb.signerMu.Lock()
+	defer cb.signerMu.Unlock()
+	if cb.signer != nil {
+		return cb.signer, nil
+	}
+	chainID, err := cb.ChainID(ctx)
+	if err != nil {
+		return nil, err
 	}
+	cb.signer = types.LatestSignerForChainID(chainID)
+	return cb.signer, nil
+}
+
+// SuggestGasPrice returns the gas price monitor's current estimate.
+func (cb *contractBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return cb.gasMonitor.GasPrice()
+}
+
+// SuggestGasTipCap retrieves the currently suggested priority fee (tip) for
+// EIP-1559 transactions and adds 10%, mirroring SuggestGasPrice. The cached
+// value is guarded by gasTipCapMu, since multiple goroutines transacting
+// from different accounts can share one contractBackend.
+func (cb *contractBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	cb.gasTipCapMu.Lock()
+	defer cb.gasTipCapMu.Unlock()
+
+	if cb.gasTipCap != nil && time.Since(cb.gasTipCapUpdated) < 1*time.Minute {
+		return cb.gasTipCap, nil
+	}
+	tip, err := cb.rawBackend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	utils.AddPercentage(tip, 10)
 	//TODO: drop to debug
 	log.WithFields(log.Fields{
-		"gasPrice": gp.Int64(),
-	}).Info("returning gas price")
-	cb.gasPriceUpdated = time.Now()
-	cb.gasPrice = gp
-	return gp, nil
+		"gasTipCap": tip.Int64(),
+	}).Info("returning gas tip cap")
+	cb.gasTipCapUpdated = time.Now()
+	cb.gasTipCap = tip
+	return tip, nil
+}
+
+// TxOpts carries the fields of a transaction that the caller wants filled
+// in, minus the pricing fields, which SendTx, SendLegacyTx, and
+// SendDynamicFeeTx compute themselves.
+type TxOpts struct {
+	ChainID *big.Int
+	From    common.Address
+	Nonce   uint64
+	To      *common.Address
+	Value   *big.Int
+	Gas     uint64
+	Data    []byte
+}
+
+// SendTx builds, signs, and sends a transaction from opts, dispatching to
+// SendLegacyTx or SendDynamicFeeTx according to cb.txMode, so callers don't
+// need to know out-of-band which pricing scheme this backend was configured
+// for.
+func (cb *contractBackend) SendTx(ctx context.Context, opts TxOpts, signTx bind.SignerFn) (*types.Transaction, error) {
+	switch cb.txMode {
+	case TxModeDynamicFee:
+		return cb.SendDynamicFeeTx(ctx, opts, signTx)
+	default:
+		return cb.SendLegacyTx(ctx, opts, signTx)
+	}
+}
+
+// SendLegacyTx builds a legacy (type-0) transaction priced at
+// SuggestGasPrice, signs it with signTx, and sends it.
+func (cb *contractBackend) SendLegacyTx(ctx context.Context, opts TxOpts, signTx bind.SignerFn) (*types.Transaction, error) {
+	gasPrice, err := cb.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    opts.Nonce,
+		GasPrice: gasPrice,
+		Gas:      opts.Gas,
+		To:       opts.To,
+		Value:    opts.Value,
+		Data:     opts.Data,
+	})
+	signedTx, err := signTx(opts.From, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := cb.SendTransaction(ctx, signedTx); err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
+
+// SendDynamicFeeTx builds a types.DynamicFeeTx using the current suggested
+// tip cap and the latest block's base fee, signs it with signTx and sends it.
+// GasFeeCap is clamped to maxPrice with a warning, whatever GasPriceMode the
+// backend was created with; unlike SuggestGasPrice, it never returns
+// ErrGasPriceTooHigh.
+func (cb *contractBackend) SendDynamicFeeTx(ctx context.Context, opts TxOpts, signTx bind.SignerFn) (*types.Transaction, error) {
+	tipCap, err := cb.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	header, err := cb.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if header.BaseFee == nil {
+		return nil, errors.New("ethereum: chain head has no base fee, this backend requires an EIP-1559 chain for dynamic-fee transactions")
+	}
+	feeCap := new(big.Int).Add(tipCap, new(big.Int).Mul(header.BaseFee, big.NewInt(2)))
+	if cb.maxPrice != nil && feeCap.Cmp(cb.maxPrice) == 1 {
+		log.WithFields(log.Fields{
+			"suggested": feeCap.Int64(),
+			"maximum":   cb.maxPrice.Int64(),
+		}).Warn("clamping gas fee cap to maximum price")
+		feeCap = cb.maxPrice
+	}
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   opts.ChainID,
+		Nonce:     opts.Nonce,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       opts.Gas,
+		To:        opts.To,
+		Value:     opts.Value,
+		Data:      opts.Data,
+	})
+	signedTx, err := signTx(opts.From, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := cb.SendTransaction(ctx, signedTx); err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
+
+// PendingNonceAt helps us count the nonce more robustly. It locks account's
+// nonce for the duration of the call, so it's safe to call directly, e.g.
+// from generated contract bindings; callers that also need to sign and send
+// externally with the same nonce should use Transact instead, since locking
+// around PendingNonceAt and SendTransaction separately still leaves a gap
+// another goroutine can land in between.
+func (cb *contractBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	cb.locker.Lock(account)
+	defer cb.locker.Unlock(account)
+	return cb.pendingNonceAtLocked(ctx, account)
 }
 
-// PendingNonceAt helps us count the nonce more robustly.
-func (cb *contractBackend) PendingNonceAt(ctx context.Context, account common.Address) (pendingNonce uint64, err error) {
+// pendingNonceAtLocked is PendingNonceAt's body, used both by PendingNonceAt
+// itself and by Transact, which already holds account's lock.
+func (cb *contractBackend) pendingNonceAtLocked(ctx context.Context, account common.Address) (pendingNonce uint64, err error) {
 	logger := log.WithField("address", account.Hex())
-	cb.lastServerNonce, err = cb.ContractBackend.PendingNonceAt(ctx, account)
+	st := cb.stateFor(account)
+	st.lastServerNonce, err = cb.rawBackend.PendingNonceAt(ctx, account)
 	if err != nil {
 		logger.WithError(err).Error("failed to get pending nonce from server")
 		return 0, err
 	}
 	logger = logger.WithFields(log.Fields{
-		"serverNonce": cb.lastServerNonce,
-		"localNonce":  cb.localNonce,
+		"serverNonce": st.lastServerNonce,
+		"localNonce":  st.localNonce,
 	})
 	switch {
-	case cb.localNonce == 0:
+	case st.localNonce == 0:
 		logger.Info("using server nonce (first time)")
-		return cb.lastServerNonce, nil
+		return st.lastServerNonce, nil
 
-	case cb.localNonce > cb.lastServerNonce && cb.localNonce-cb.lastServerNonce >= maxNonceDrift:
+	case st.localNonce > st.lastServerNonce && st.localNonce-st.lastServerNonce >= maxNonceDrift:
 		logger.Warn("resetted local nonce")
-		cb.resetNonce()
-		return cb.lastServerNonce, nil
+		st.reset()
+		return st.lastServerNonce, nil
 
 	default:
 		logger.Info("using local nonce")
-		return cb.localNonce, nil
+		return st.localNonce, nil
 	}
 }
 
-const (
-	errStrReplacementTx = "replacement transaction underpriced"
-)
+// replacementErrs are the sentinel errors go-ethereum returns for a tx that
+// lost to an already-pending one at the same nonce. errors.Is matches these
+// when the backend is in-process (e.g. the simulated package); a remote node
+// only ever returns the message text over JSON-RPC, so replacementErrStrs
+// covers that case too.
+var replacementErrs = []error{
+	core.ErrNonceTooLow,
+	txpool.ErrAlreadyKnown,
+	txpool.ErrReplaceUnderpriced,
+}
+
+var replacementErrStrs = []string{
+	"replacement transaction underpriced",
+	"nonce too low",
+	"already known",
+}
 
 func isReplacementErr(err error) bool {
-	return err.Error() == errStrReplacementTx
+	if err == nil {
+		return false
+	}
+	for _, sentinel := range replacementErrs {
+		if errors.Is(err, sentinel) {
+			return true
+		}
+	}
+	msg := err.Error()
+	for _, s := range replacementErrStrs {
+		if msg == s {
+			return true
+		}
+	}
+	return false
 }
 
-// SendTransaction sends the transaction with the most up-to-date nonce.
+// SendTransaction sends tx, keyed by its signature's recovered sender. It
+// locks that account's nonce for the duration of the call, so it's safe to
+// call directly, e.g. from generated contract bindings; callers that also
+// need to pick the nonce and sign externally should use Transact instead,
+// since locking around PendingNonceAt and SendTransaction separately still
+// leaves a gap another goroutine can land in between.
 func (cb *contractBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	signer, err := cb.txSigner(ctx)
+	if err != nil {
+		getTxLogger(tx).WithError(err).Error("failed to resolve signer for sender nonce tracking")
+		return err
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		getTxLogger(tx).WithError(err).Error("failed to recover sender from transaction")
+		return err
+	}
+
+	cb.locker.Lock(from)
+	defer cb.locker.Unlock(from)
+	return cb.sendTransactionLocked(ctx, tx, from)
+}
+
+// sendTransactionLocked is SendTransaction's body, used both by
+// SendTransaction itself and by Transact, which already holds from's lock.
+func (cb *contractBackend) sendTransactionLocked(ctx context.Context, tx *types.Transaction, from common.Address) error {
 	logger := getTxLogger(tx)
 	logger.Info("sending")
-	if err := cb.ContractBackend.SendTransaction(ctx, tx); err != nil {
+
+	st := cb.stateFor(from)
+	if err := cb.rawBackend.SendTransaction(ctx, tx); err != nil {
 		// quickly go back to the last server nonce when the error repeats
 		if isReplacementErr(err) {
-			cb.resetNonce()
+			st.reset()
 		}
 		logger.WithError(err).Error("failed to send")
 		return err
 	}
 	logger.Info("sent")
 	// count it locally: if sending the tx is successful than that's the previous nonce for sure
-	cb.incrementNonce(tx)
+	st.increment(tx)
+	// only tracked for reorg rollback once someone is actually watching for
+	// reorgs; otherwise nothing ever drains st.sent and it grows forever.
+	if cb.reorgWatchActive.Load() {
+		st.sent[tx.Hash()] = &sentTxInfo{nonce: tx.Nonce()}
+	}
+	return nil
+}
+
+// AdjustNonce forcibly sets addr's locally tracked nonce to target, or, if
+// target is nil, rolls it back to the last nonce observed from the server.
+// Use this to force a resync after an L1 reorg evicts a tx whose nonce the
+// local count already advanced past.
+func (cb *contractBackend) AdjustNonce(addr common.Address, target *uint64) {
+	cb.locker.Lock(addr)
+	defer cb.locker.Unlock(addr)
+	st := cb.stateFor(addr)
+	if target != nil {
+		st.localNonce = *target
+		return
+	}
+	st.reset()
+}
+
+// reorgWatchDepth is how many blocks a mined tx is watched for disappearing
+// from the canonical chain before WatchReorgs stops tracking it.
+const reorgWatchDepth = 12
+
+// WatchReorgs subscribes to new heads over rpcClient and, for every tracked
+// account, rolls the local nonce back to a transaction's nonce if that
+// transaction, sent by this backend and seen mined, no longer has a receipt
+// within reorgWatchDepth blocks of its mined block, i.e. it fell out of the
+// canonical chain. It runs until ctx is cancelled or the new heads
+// subscription fails.
+func (cb *contractBackend) WatchReorgs(ctx context.Context, rpcClient *rpc.Client) error {
+	ec := ethclient.NewClient(rpcClient)
+	heads := make(chan *types.Header)
+	sub, err := ec.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return err
+	}
+	cb.reorgWatchActive.Store(true)
+	go func() {
+		defer sub.Unsubscribe()
+		defer cb.reorgWatchActive.Store(false)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-sub.Err():
+				log.WithError(err).Warn("new heads subscription ended")
+				return
+			case head := <-heads:
+				cb.checkForReorgs(ctx, ec, head)
+			}
+		}
+	}()
 	return nil
 }
 
-func (cb *contractBackend) incrementNonce(tx *types.Transaction) {
-	newNonce := tx.Nonce() + 1
-	if newNonce > cb.localNonce {
-		cb.localNonce = newNonce
+func (cb *contractBackend) checkForReorgs(ctx context.Context, ec *ethclient.Client, head *types.Header) {
+	cb.statesMu.Lock()
+	addrs := make([]common.Address, 0, len(cb.nonces))
+	for addr := range cb.nonces {
+		addrs = append(addrs, addr)
+	}
+	cb.statesMu.Unlock()
+
+	for _, addr := range addrs {
+		cb.checkAddrForReorgs(ctx, ec, addr, head)
 	}
 }
 
-func (cb *contractBackend) resetNonce() {
-	if cb.lastServerNonce < cb.localNonce {
-		cb.localNonce = cb.lastServerNonce
+func (cb *contractBackend) checkAddrForReorgs(ctx context.Context, ec *ethclient.Client, addr common.Address, head *types.Header) {
+	cb.locker.Lock(addr)
+	defer cb.locker.Unlock(addr)
+
+	st := cb.stateFor(addr)
+	for hash, info := range st.sent {
+		if !info.mined {
+			receipt, err := ec.TransactionReceipt(ctx, hash)
+			if err != nil {
+				continue
+			}
+			info.mined = true
+			info.minedBlock = receipt.BlockNumber.Uint64()
+			continue
+		}
+
+		if head.Number.Uint64() >= info.minedBlock+reorgWatchDepth {
+			// buried deep enough that we stop worrying about it
+			delete(st.sent, hash)
+			continue
+		}
+
+		if _, err := ec.TransactionReceipt(ctx, hash); errors.Is(err, geth.NotFound) {
+			log.WithFields(log.Fields{
+				"address": addr.Hex(),
+				"hash":    hash.Hex(),
+				"nonce":   info.nonce,
+			}).Warn("mined transaction vanished from canonical chain, rolling back local nonce")
+			if info.nonce < st.localNonce {
+				st.localNonce = info.nonce
+			}
+			delete(st.sent, hash)
+		}
 	}
 }
 
 func getTxLogger(tx *types.Transaction) *log.Entry {
-	return log.WithFields(log.Fields{
+	fields := log.Fields{
 		"to":       tx.To().Hex(),
 		"nonce":    tx.Nonce(),
 		"gasLimit": tx.Gas(),
-		"gasPrice": tx.GasPrice().Uint64(),
 		"hash":     tx.Hash().Hex(),
-	})
+	}
+	switch tx.Type() {
+	case types.DynamicFeeTxType:
+		fields["gasTipCap"] = tx.GasTipCap().Uint64()
+		fields["gasFeeCap"] = tx.GasFeeCap().Uint64()
+	default:
+		fields["gasPrice"] = tx.GasPrice().Uint64()
+	}
+	return log.WithFields(fields)
 }
@@ -0,0 +1,190 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/forta-protocol/forta-node/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// GasPriceMode selects what a GasPriceMonitor does when the current price
+// exceeds MaxPrice.
+type GasPriceMode int
+
+const (
+	// GasPriceModeClamp returns MaxPrice and logs a warning.
+	GasPriceModeClamp GasPriceMode = iota
+	// GasPriceModeRefuse returns ErrGasPriceTooHigh instead of a price, so
+	// callers refuse to transact rather than overpay.
+	GasPriceModeRefuse
+)
+
+// ErrGasPriceTooHigh is returned by GasPriceMonitor.GasPrice when running in
+// GasPriceModeRefuse and the current price exceeds MaxPrice.
+var ErrGasPriceTooHigh = errors.New("ethereum: suggested gas price exceeds configured maximum")
+
+// gasPricer is the subset of bind.ContractTransactor a GasPriceMonitor polls.
+type gasPricer interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// GasPriceMonitorConfig configures a GasPriceMonitor.
+type GasPriceMonitorConfig struct {
+	// PollInterval is how often the monitor polls the backend. Defaults to 1m.
+	PollInterval time.Duration
+	// MaxPrice is the ceiling enforced according to Mode. Nil disables it.
+	MaxPrice *big.Int
+	// Mode selects what happens when the price exceeds MaxPrice.
+	Mode GasPriceMode
+	// SignificantChangePct is the percentage move, up or down, from the last
+	// pushed price that triggers a Subscribe update. Defaults to 10.
+	SignificantChangePct int64
+}
+
+func (cfg GasPriceMonitorConfig) withDefaults() GasPriceMonitorConfig {
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = 1 * time.Minute
+	}
+	if cfg.SignificantChangePct == 0 {
+		cfg.SignificantChangePct = 10
+	}
+	return cfg
+}
+
+// GasPriceMonitor polls a backend for the suggested gas price on an
+// interval, keeps the latest padded sample, and pushes it to subscribers
+// when it moves significantly. contractBackend.SuggestGasPrice delegates to
+// one of these instead of caching the price itself.
+type GasPriceMonitor struct {
+	backend gasPricer
+	cfg     GasPriceMonitorConfig
+
+	mu         sync.RWMutex
+	price      *big.Int
+	lastPushed *big.Int
+	subs       []chan *big.Int
+
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewGasPriceMonitor creates a GasPriceMonitor over backend. Call Start to
+// begin polling.
+func NewGasPriceMonitor(backend gasPricer, cfg GasPriceMonitorConfig) *GasPriceMonitor {
+	return &GasPriceMonitor{
+		backend: backend,
+		cfg:     cfg.withDefaults(),
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// Start begins the background polling loop, sampling once immediately so
+// GasPrice has a value without waiting a full interval.
+func (m *GasPriceMonitor) Start(ctx context.Context) {
+	go m.loop(ctx)
+}
+
+// Stop ends the polling loop and waits for it to exit.
+func (m *GasPriceMonitor) Stop() {
+	close(m.closeCh)
+	<-m.doneCh
+}
+
+func (m *GasPriceMonitor) loop(ctx context.Context) {
+	defer close(m.doneCh)
+	m.poll(ctx)
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.closeCh:
+			return
+		case <-ticker.C:
+			m.poll(ctx)
+		}
+	}
+}
+
+func (m *GasPriceMonitor) poll(ctx context.Context) {
+	sample, err := m.backend.SuggestGasPrice(ctx)
+	if err != nil {
+		log.WithError(err).Warn("gas price monitor failed to poll backend")
+		return
+	}
+	utils.AddPercentage(sample, 10)
+
+	m.mu.Lock()
+	m.price = sample
+	shouldPush := m.lastPushed == nil || changedSignificantly(m.lastPushed, sample, m.cfg.SignificantChangePct)
+	if shouldPush {
+		m.lastPushed = sample
+	}
+	subs := append([]chan *big.Int(nil), m.subs...)
+	m.mu.Unlock()
+
+	if !shouldPush {
+		return
+	}
+	for _, sub := range subs {
+		select {
+		case sub <- sample:
+		default:
+			log.Warn("gas price subscriber channel full, dropping update")
+		}
+	}
+}
+
+func changedSignificantly(prev, next *big.Int, pct int64) bool {
+	if prev.Sign() == 0 {
+		return next.Sign() != 0
+	}
+	diff := new(big.Int).Sub(next, prev)
+	diff.Abs(diff)
+	threshold := new(big.Int).Mul(prev, big.NewInt(pct))
+	threshold.Div(threshold, big.NewInt(100))
+	return diff.Cmp(threshold) >= 0
+}
+
+// GasPrice returns the monitor's current gas price estimate, applying
+// MaxPrice according to Mode.
+func (m *GasPriceMonitor) GasPrice() (*big.Int, error) {
+	m.mu.RLock()
+	price := m.price
+	m.mu.RUnlock()
+	if price == nil {
+		return nil, errors.New("ethereum: gas price monitor has no sample yet")
+	}
+	if m.cfg.MaxPrice != nil && price.Cmp(m.cfg.MaxPrice) == 1 {
+		if m.cfg.Mode == GasPriceModeRefuse {
+			return nil, ErrGasPriceTooHigh
+		}
+		log.WithFields(log.Fields{
+			"suggested": price.Int64(),
+			"maximum":   m.cfg.MaxPrice.Int64(),
+		}).Warn("returning maximum price")
+		return m.cfg.MaxPrice, nil
+	}
+	return price, nil
+}
+
+// MaxGasPrice returns the configured ceiling, or nil if unset.
+func (m *GasPriceMonitor) MaxGasPrice() *big.Int {
+	return m.cfg.MaxPrice
+}
+
+// Subscribe returns a channel that receives the first sample and then the
+// current price whenever it moves by at least SignificantChangePct percent
+// from the last pushed value.
+func (m *GasPriceMonitor) Subscribe() <-chan *big.Int {
+	ch := make(chan *big.Int, 1)
+	m.mu.Lock()
+	m.subs = append(m.subs, ch)
+	m.mu.Unlock()
+	return ch
+}
@@ -0,0 +1,132 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scriptedGasPricer is a gasPricer that returns a scripted sequence of
+// prices, repeating the last one once exhausted.
+type scriptedGasPricer struct {
+	mu     sync.Mutex
+	prices []*big.Int
+	next   int
+}
+
+func (s *scriptedGasPricer) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.next
+	if i >= len(s.prices) {
+		i = len(s.prices) - 1
+	} else {
+		s.next++
+	}
+	return new(big.Int).Set(s.prices[i]), nil
+}
+
+func bigFromInt64(v int64) *big.Int {
+	return big.NewInt(v)
+}
+
+func TestGasPriceMonitorStartStop(t *testing.T) {
+	backend := &scriptedGasPricer{prices: []*big.Int{bigFromInt64(100)}}
+	monitor := NewGasPriceMonitor(backend, GasPriceMonitorConfig{PollInterval: time.Hour})
+	monitor.Start(context.Background())
+
+	deadline := time.Now().Add(time.Second)
+	var price *big.Int
+	var err error
+	for time.Now().Before(deadline) {
+		price, err = monitor.GasPrice()
+		if err == nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("GasPrice never produced a sample: %v", err)
+	}
+	if want := bigFromInt64(110); price.Cmp(want) != 0 {
+		t.Errorf("GasPrice() = %s, want %s", price, want)
+	}
+
+	monitor.Stop()
+	select {
+	case <-monitor.doneCh:
+	default:
+		t.Error("Stop returned before the poll loop exited")
+	}
+}
+
+func TestGasPriceMonitorModeClamp(t *testing.T) {
+	backend := &scriptedGasPricer{prices: []*big.Int{bigFromInt64(1000)}}
+	monitor := NewGasPriceMonitor(backend, GasPriceMonitorConfig{
+		MaxPrice: bigFromInt64(500),
+		Mode:     GasPriceModeClamp,
+	})
+	monitor.poll(context.Background())
+
+	price, err := monitor.GasPrice()
+	if err != nil {
+		t.Fatalf("GasPrice() returned unexpected error: %v", err)
+	}
+	if want := bigFromInt64(500); price.Cmp(want) != 0 {
+		t.Errorf("GasPrice() = %s, want clamped %s", price, want)
+	}
+}
+
+func TestGasPriceMonitorModeRefuse(t *testing.T) {
+	backend := &scriptedGasPricer{prices: []*big.Int{bigFromInt64(1000)}}
+	monitor := NewGasPriceMonitor(backend, GasPriceMonitorConfig{
+		MaxPrice: bigFromInt64(500),
+		Mode:     GasPriceModeRefuse,
+	})
+	monitor.poll(context.Background())
+
+	_, err := monitor.GasPrice()
+	if !errors.Is(err, ErrGasPriceTooHigh) {
+		t.Errorf("GasPrice() error = %v, want ErrGasPriceTooHigh", err)
+	}
+}
+
+func TestGasPriceMonitorSubscribeSignificantChange(t *testing.T) {
+	backend := &scriptedGasPricer{prices: []*big.Int{
+		bigFromInt64(1000), // first sample always pushes
+		bigFromInt64(1020), // +2%, below the 10% threshold, no push
+		bigFromInt64(1300), // +30% vs the last pushed value, pushes
+	}}
+	monitor := NewGasPriceMonitor(backend, GasPriceMonitorConfig{SignificantChangePct: 10})
+	sub := monitor.Subscribe()
+
+	monitor.poll(context.Background())
+	select {
+	case price := <-sub:
+		if want := bigFromInt64(1100); price.Cmp(want) != 0 {
+			t.Errorf("first push = %s, want %s", price, want)
+		}
+	default:
+		t.Fatal("expected a push on the first sample")
+	}
+
+	monitor.poll(context.Background())
+	select {
+	case price := <-sub:
+		t.Errorf("unexpected push for an insignificant change: %s", price)
+	default:
+	}
+
+	monitor.poll(context.Background())
+	select {
+	case price := <-sub:
+		if want := bigFromInt64(1430); price.Cmp(want) != 0 {
+			t.Errorf("third push = %s, want %s", price, want)
+		}
+	default:
+		t.Fatal("expected a push once the price moved significantly again")
+	}
+}
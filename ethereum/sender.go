@@ -0,0 +1,324 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/forta-protocol/forta-node/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// TxConfirm is delivered on a tracked transaction's channel once it has been
+// mined and buried under the configured number of confirmations.
+type TxConfirm struct {
+	Tx            *types.Transaction
+	Receipt       *types.Receipt
+	Confirmations uint64
+}
+
+// TxBuilder builds an unsigned replacement for a stuck transaction at the
+// given nonce and gas price. It's invoked by SendBuild for the initial
+// transaction and by Sender when it decides to rebroadcast; Cancel builds its
+// own self-transfer and doesn't use it.
+type TxBuilder func(nonce uint64, gasPrice *big.Int) *types.Transaction
+
+// SenderConfig configures a Sender.
+type SenderConfig struct {
+	// Confirmations is how many blocks must be mined on top of a tx's block
+	// before it is considered confirmed. Defaults to 1.
+	Confirmations uint64
+	// PollInterval is how often the sender checks receipts and the chain
+	// head. Defaults to 5s.
+	PollInterval time.Duration
+	// ResendTimeout is how long the sender waits for a tx to be mined before
+	// rebroadcasting it at a bumped gas price. Defaults to 2m.
+	ResendTimeout time.Duration
+	// MaxPrice caps the gas price a rebroadcast can reach.
+	MaxPrice *big.Int
+}
+
+func (cfg SenderConfig) withDefaults() SenderConfig {
+	if cfg.Confirmations == 0 {
+		cfg.Confirmations = 1
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.ResendTimeout == 0 {
+		cfg.ResendTimeout = 2 * time.Minute
+	}
+	return cfg
+}
+
+// pendingTx is the sender's bookkeeping for one in-flight transaction.
+type pendingTx struct {
+	id        uint64
+	from      common.Address
+	nonce     uint64
+	gasPrice  *big.Int
+	build     TxBuilder
+	tx        *types.Transaction
+	sentAt    time.Time
+	confirmCh chan TxConfirm
+}
+
+// Sender owns the full lifecycle of transactions sent through a
+// ContractBackend: submission, confirmation tracking, and automatic
+// replacement of stuck transactions. It replaces fire-and-forget calls to
+// ContractBackend.SendTransaction with a supervised queue.
+type Sender struct {
+	backend ContractBackend
+	sign    bind.SignerFn
+	cfg     SenderConfig
+
+	mu      sync.Mutex
+	pending map[uint64]*pendingTx
+	nextID  uint64
+
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewSender creates a Sender on top of backend and starts its background
+// confirmation/resend loop. sign is used to re-sign replacement transactions
+// that Sender builds on the caller's behalf (resends and cancellations).
+func NewSender(backend ContractBackend, sign bind.SignerFn, cfg SenderConfig) *Sender {
+	s := &Sender{
+		backend: backend,
+		sign:    sign,
+		cfg:     cfg.withDefaults(),
+		pending: make(map[uint64]*pendingTx),
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	go s.loop()
+	return s
+}
+
+// Send submits tx, which must already be signed, and tracks it for
+// confirmation. build, if non-nil, rebuilds an equivalent unsigned
+// transaction at the same nonce and a higher gas price, and is used to
+// rebroadcast tx if it gets stuck; without it a stuck tx is only logged.
+// The returned channel receives exactly one TxConfirm once tx reaches the
+// configured number of confirmations. Callers that want Sender to pick the
+// nonce and sign the initial transaction too should use SendBuild instead.
+func (s *Sender) Send(ctx context.Context, from common.Address, tx *types.Transaction, build TxBuilder) (id uint64, confirmCh <-chan TxConfirm, err error) {
+	if err := s.backend.SendTransaction(ctx, tx); err != nil {
+		return 0, nil, err
+	}
+	id = s.track(from, tx, build)
+	return id, s.confirmChFor(id), nil
+}
+
+// SendBuild builds an unsigned transaction via build at the account's next
+// nonce and gasPrice, signs it, and sends it through backend.Transact, which
+// holds from's nonce lock for the whole fetch-build-sign-send sequence. It
+// otherwise behaves like Send: build is kept to rebroadcast the transaction
+// at a bumped gas price if it gets stuck.
+func (s *Sender) SendBuild(ctx context.Context, from common.Address, gasPrice *big.Int, build TxBuilder) (id uint64, confirmCh <-chan TxConfirm, err error) {
+	tx, err := s.backend.Transact(ctx, from, func(nonce uint64) (*types.Transaction, error) {
+		return build(nonce, gasPrice), nil
+	}, s.sign)
+	if err != nil {
+		return 0, nil, err
+	}
+	id = s.track(from, tx, build)
+	return id, s.confirmChFor(id), nil
+}
+
+// track records tx as pending confirmation under a new ID and returns it.
+func (s *Sender) track(from common.Address, tx *types.Transaction, build TxBuilder) uint64 {
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	s.pending[id] = &pendingTx{
+		id:        id,
+		from:      from,
+		nonce:     tx.Nonce(),
+		gasPrice:  effectiveGasPrice(tx),
+		build:     build,
+		tx:        tx,
+		sentAt:    time.Now(),
+		confirmCh: make(chan TxConfirm, 1),
+	}
+	s.mu.Unlock()
+
+	log.WithFields(log.Fields{
+		"id":    id,
+		"hash":  tx.Hash().Hex(),
+		"nonce": tx.Nonce(),
+	}).Info("tracking transaction")
+	return id
+}
+
+func (s *Sender) confirmChFor(id uint64) <-chan TxConfirm {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending[id].confirmCh
+}
+
+// Pending returns the IDs of all transactions the sender is still tracking.
+func (s *Sender) Pending() []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]uint64, 0, len(s.pending))
+	for id := range s.pending {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Cancel aborts the pending transaction identified by id by rebroadcasting a
+// 0-value self-transfer at the same nonce and a higher gas price.
+func (s *Sender) Cancel(ctx context.Context, id uint64) error {
+	s.mu.Lock()
+	p, ok := s.pending[id]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no pending transaction with id %d", id)
+	}
+
+	bumped := bumpGasPrice(p.gasPrice, s.cfg.MaxPrice)
+	cancelTx, err := s.backend.Transact(ctx, p.from, func(uint64) (*types.Transaction, error) {
+		return types.NewTransaction(p.nonce, p.from, big.NewInt(0), 21000, bumped, nil), nil
+	}, s.sign)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	p.tx = cancelTx
+	p.gasPrice = bumped
+	p.sentAt = time.Now()
+	s.mu.Unlock()
+
+	log.WithFields(log.Fields{"id": id, "hash": cancelTx.Hash().Hex()}).Warn("cancelled transaction")
+	return nil
+}
+
+// Stop ends the background loop and waits for it to exit. It does not wait
+// for still-pending transactions to confirm.
+func (s *Sender) Stop() {
+	close(s.closeCh)
+	<-s.doneCh
+}
+
+func (s *Sender) loop() {
+	defer close(s.doneCh)
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case <-ticker.C:
+			s.poll()
+		}
+	}
+}
+
+func (s *Sender) poll() {
+	ctx := context.Background()
+	head, err := s.backend.BlockNumber(ctx)
+	if err != nil {
+		log.WithError(err).Warn("failed to get latest block number")
+		return
+	}
+
+	s.mu.Lock()
+	txs := make([]*pendingTx, 0, len(s.pending))
+	for _, p := range s.pending {
+		txs = append(txs, p)
+	}
+	s.mu.Unlock()
+
+	for _, p := range txs {
+		s.checkTx(ctx, p, head)
+	}
+}
+
+func (s *Sender) checkTx(ctx context.Context, p *pendingTx, head uint64) {
+	receipt, err := s.backend.TransactionReceipt(ctx, p.tx.Hash())
+	if err != nil {
+		if time.Since(p.sentAt) > s.cfg.ResendTimeout {
+			s.resend(ctx, p)
+		}
+		return
+	}
+
+	var confirmations uint64
+	if head >= receipt.BlockNumber.Uint64() {
+		confirmations = head - receipt.BlockNumber.Uint64()
+	}
+	if confirmations < s.cfg.Confirmations {
+		return
+	}
+
+	s.mu.Lock()
+	delete(s.pending, p.id)
+	s.mu.Unlock()
+
+	p.confirmCh <- TxConfirm{Tx: p.tx, Receipt: receipt, Confirmations: confirmations}
+	close(p.confirmCh)
+	log.WithFields(log.Fields{
+		"id":            p.id,
+		"hash":          p.tx.Hash().Hex(),
+		"confirmations": confirmations,
+	}).Info("transaction confirmed")
+}
+
+// resend rebroadcasts a stuck transaction at a bumped gas price instead of
+// surfacing "replacement transaction underpriced" to the caller.
+func (s *Sender) resend(ctx context.Context, p *pendingTx) {
+	if p.build == nil {
+		log.WithField("id", p.id).Warn("stuck transaction has no builder, cannot resend")
+		return
+	}
+
+	bumped := bumpGasPrice(p.gasPrice, s.cfg.MaxPrice)
+	tx, err := s.backend.Transact(ctx, p.from, func(uint64) (*types.Transaction, error) {
+		return p.build(p.nonce, bumped), nil
+	}, s.sign)
+	if err != nil {
+		if !isReplacementErr(err) {
+			log.WithError(err).WithField("id", p.id).Error("failed to resend stuck transaction")
+		}
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"id":       p.id,
+		"oldHash":  p.tx.Hash().Hex(),
+		"newHash":  tx.Hash().Hex(),
+		"gasPrice": bumped.Int64(),
+	}).Warn("resent stuck transaction with bumped gas price")
+	p.tx = tx
+	p.gasPrice = bumped
+	p.sentAt = time.Now()
+}
+
+// bumpGasPrice adds 10% to price, clamped to maxPrice when set.
+func bumpGasPrice(price, maxPrice *big.Int) *big.Int {
+	bumped := new(big.Int).Set(price)
+	utils.AddPercentage(bumped, 10)
+	if maxPrice != nil && bumped.Cmp(maxPrice) == 1 {
+		return new(big.Int).Set(maxPrice)
+	}
+	return bumped
+}
+
+// effectiveGasPrice returns the price that governs whether a resend counts
+// as a bump: the gas price for legacy txs, the fee cap for EIP-1559 ones.
+func effectiveGasPrice(tx *types.Transaction) *big.Int {
+	if tx.Type() == types.DynamicFeeTxType {
+		return tx.GasFeeCap()
+	}
+	return tx.GasPrice()
+}
@@ -0,0 +1,70 @@
+// Package simulated wraps an in-memory dev-mode geth node behind this
+// module's ethereum.ContractBackend, so the rest of the module (agent
+// registry, scanner, publisher) can run integration-style tests against a
+// real EVM without spinning up ganache or anvil.
+package simulated
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/forta-protocol/forta-node/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	gethsimulated "github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+// Backend is a simulated chain paired with a contract backend that behaves
+// like production: same nonce-drift handling, gas-bumping, and (where
+// configured) EIP-1559 pricing as contractBackend.
+type Backend struct {
+	*gethsimulated.Backend
+
+	contractBackend ethereum.ContractBackend
+}
+
+// NewBackend creates a Backend funding each of accounts with balance wei at
+// genesis, with a contract backend running in txMode/gasPriceMode.
+func NewBackend(accounts []common.Address, balance *big.Int, txMode ethereum.TxMode, gasPriceMode ethereum.GasPriceMode) *Backend {
+	alloc := types.GenesisAlloc{}
+	for _, addr := range accounts {
+		alloc[addr] = types.Account{Balance: balance}
+	}
+	backend := gethsimulated.NewBackend(alloc)
+	return &Backend{
+		Backend:         backend,
+		contractBackend: ethereum.NewContractBackendWithRawBackend(backend.Client(), nil, txMode, gasPriceMode),
+	}
+}
+
+// ContractBackend returns the wrapped backend, ready to drive bound
+// contract instances against the simulated chain.
+func (b *Backend) ContractBackend() ethereum.ContractBackend {
+	return b.contractBackend
+}
+
+// AdvanceTime adjusts the simulated clock forward by d. The simulated
+// backend's AdjustTime does this by sealing a new block with the adjusted
+// timestamp.
+func (b *Backend) AdvanceTime(d time.Duration) error {
+	return b.Backend.AdjustTime(d)
+}
+
+// Snapshot records the current chain head so a later Revert can roll back
+// to it, e.g. to simulate a reorg.
+func (b *Backend) Snapshot(ctx context.Context) (common.Hash, error) {
+	header, err := b.Client().HeaderByNumber(ctx, nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Hash(), nil
+}
+
+// Revert forks the chain back to the block recorded by Snapshot, discarding
+// everything mined since, including any nonces the local contract backend
+// thought it had confirmed.
+func (b *Backend) Revert(snapshot common.Hash) error {
+	return b.Backend.Fork(snapshot)
+}